@@ -0,0 +1,253 @@
+//Package broker talks to a Pact Broker's HAL API so a Verifier can source pacts from
+//"pacts for verification" selectors instead of a single hard-coded pact uri, and publish
+//verification results back once Verify() has run.
+package broker
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/SEEK-Jobs/pact-go/io"
+)
+
+//ConsumerVersionSelector narrows which consumer pact versions the broker returns from the
+//pacts-for-verification endpoint. Only set the fields relevant to the selector you want,
+//e.g. {Latest: true}, {Branch: "main"} or {MainBranch: true}.
+type ConsumerVersionSelector struct {
+	Tag                string `json:"tag,omitempty"`
+	Branch             string `json:"branch,omitempty"`
+	MainBranch         bool   `json:"mainBranch,omitempty"`
+	MatchingBranch     bool   `json:"matchingBranch,omitempty"`
+	Latest             bool   `json:"latest,omitempty"`
+	DeployedOrReleased bool   `json:"deployedOrReleased,omitempty"`
+	Environment        string `json:"environment,omitempty"`
+}
+
+//BrokerConfig configures how pacts are sourced from, and verification results published
+//to, a Pact Broker
+type BrokerConfig struct {
+	Username    string
+	Password    string
+	BearerToken string
+
+	Selectors []ConsumerVersionSelector
+
+	ProviderVersion       string
+	ProviderVersionTags   []string
+	ProviderVersionBranch string
+	BuildURL              string
+
+	//PublishResults controls whether verification results are posted back to the broker
+	PublishResults bool
+}
+
+type halLink struct {
+	Href string `json:"href"`
+}
+
+//Pact is a single consumer pact returned by the pacts-for-verification endpoint, along
+//with the HAL links needed to fetch its contents and publish verification results against it
+type Pact struct {
+	VerificationProperties struct {
+		Pending bool `json:"pending"`
+		WIP     bool `json:"wip"`
+	} `json:"verificationProperties"`
+	Links map[string]halLink `json:"_links"`
+}
+
+//Pending reports whether the broker marked this pact as pending, i.e. its provider
+//states have not yet been successfully verified against any version of the provider
+func (p *Pact) Pending() bool {
+	return p.VerificationProperties.Pending
+}
+
+//WIP reports whether this pact was included because it is a work-in-progress pact
+//rather than one formally requiring verification
+func (p *Pact) WIP() bool {
+	return p.VerificationProperties.WIP
+}
+
+type pactsForVerificationRequest struct {
+	ProviderVersionBranch    string                    `json:"providerVersionBranch,omitempty"`
+	ConsumerVersionSelectors []ConsumerVersionSelector `json:"consumerVersionSelectors"`
+	IncludePending           bool                      `json:"includePending,omitempty"`
+	IncludeWipPactsSince     string                    `json:"includeWipPactsSince,omitempty"`
+}
+
+type pactsForVerificationResponse struct {
+	Embedded struct {
+		Pacts []Pact `json:"pacts"`
+	} `json:"_embedded"`
+}
+
+//InteractionResult is the pass/fail outcome of a single interaction, ready to publish to the
+//broker. There's no interaction id to report here: consumer.Interaction doesn't carry the
+//broker's own id for it, only the description recorded in the pact file.
+type InteractionResult struct {
+	Success bool `json:"success"`
+	Pending bool `json:"pending,omitempty"`
+}
+
+//VerificationResult is the outcome of verifying one pact against the provider
+type VerificationResult struct {
+	Success                    bool                `json:"success"`
+	ProviderApplicationVersion string              `json:"providerApplicationVersion"`
+	ProviderVersionTags        []string            `json:"providerVersionTags,omitempty"`
+	ProviderVersionBranch      string              `json:"providerVersionBranch,omitempty"`
+	BuildURL                   string              `json:"buildUrl,omitempty"`
+	TestResults                []InteractionResult `json:"testResults,omitempty"`
+}
+
+//Client talks to a Pact Broker's HAL API on behalf of a single BrokerConfig
+type Client struct {
+	brokerURL string
+	config    *BrokerConfig
+	http      *http.Client
+}
+
+//NewClient creates a client for the broker located at brokerURL
+func NewClient(brokerURL string, config *BrokerConfig) *Client {
+	return &Client{brokerURL: brokerURL, config: config, http: http.DefaultClient}
+}
+
+//PactsForVerification fetches the pacts the provider needs to verify, matching the configured
+//consumer version selectors. When includePending is true, pacts that have not yet been
+//successfully verified against any version of the provider are also returned. When
+//includeWipPactsSince is non-zero, work-in-progress pacts created since that time are too.
+func (c *Client) PactsForVerification(provider string, includePending bool, includeWipPactsSince time.Time) ([]*Pact, error) {
+	reqBody := pactsForVerificationRequest{
+		ProviderVersionBranch:    c.config.ProviderVersionBranch,
+		ConsumerVersionSelectors: c.config.Selectors,
+		IncludePending:           includePending,
+	}
+	if !includeWipPactsSince.IsZero() {
+		reqBody.IncludeWipPactsSince = includeWipPactsSince.Format("2006-01-02")
+	}
+
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/pacts/provider/%s/for-verification", c.brokerURL, provider), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/hal+json")
+	c.authenticate(req)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("broker returned unexpected status %s fetching pacts for verification", resp.Status)
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed pactsForVerificationResponse
+	if err := json.Unmarshal(respBody, &parsed); err != nil {
+		return nil, err
+	}
+
+	pacts := make([]*Pact, len(parsed.Embedded.Pacts))
+	for i := range parsed.Embedded.Pacts {
+		pacts[i] = &parsed.Embedded.Pacts[i]
+	}
+	return pacts, nil
+}
+
+//Fetch downloads and parses the pact file contents referenced by the pact's self link
+func (c *Client) Fetch(p *Pact) (*io.PactFile, error) {
+	self, ok := p.Links["self"]
+	if !ok {
+		return nil, errors.New("The pact returned by the broker is missing its self link.")
+	}
+
+	req, err := http.NewRequest(http.MethodGet, self.Href, nil)
+	if err != nil {
+		return nil, err
+	}
+	c.authenticate(req)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("broker returned unexpected status %s fetching pact contents", resp.Status)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &io.PactFile{}
+	if err := json.Unmarshal(body, f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+//PublishVerificationResults posts result to the pact's pb:publish-verification-results link.
+//If the broker did not advertise that link (e.g. the pact was sourced from a read-only mirror)
+//this is a no-op.
+func (c *Client) PublishVerificationResults(p *Pact, result *VerificationResult) error {
+	link, ok := p.Links["pb:publish-verification-results"]
+	if !ok {
+		return nil
+	}
+
+	result.ProviderApplicationVersion = c.config.ProviderVersion
+	result.ProviderVersionTags = c.config.ProviderVersionTags
+	result.ProviderVersionBranch = c.config.ProviderVersionBranch
+	result.BuildURL = c.config.BuildURL
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, link.Href, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	c.authenticate(req)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("broker returned unexpected status %s publishing verification results", resp.Status)
+	}
+	return nil
+}
+
+func (c *Client) authenticate(req *http.Request) {
+	if c.config.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.config.BearerToken)
+		return
+	}
+	if c.config.Username != "" {
+		req.SetBasicAuth(c.config.Username, c.config.Password)
+	}
+}
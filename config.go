@@ -0,0 +1,83 @@
+package pact
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/SEEK-Jobs/pact-go/broker"
+	"github.com/SEEK-Jobs/pact-go/io"
+)
+
+//Logger is the logging abstraction used throughout the verifier
+type Logger interface {
+	Printf(format string, args ...interface{})
+}
+
+//VerfierConfig controls the behaviour of a Verifier
+type VerfierConfig struct {
+	Logger Logger
+
+	//IncludePendingPacts asks the broker to also return pacts that have not yet been
+	//successfully verified against any version of the provider. Failures in these
+	//interactions are logged and reported but do not fail Verify(). Only takes effect
+	//when pacts are sourced via PactBroker.
+	IncludePendingPacts bool
+
+	//IncludeWipPactsSince additionally includes work-in-progress pacts - ones created since
+	//the given time that don't yet have a matching provider tag/branch - with the same
+	//non-failing treatment as pending pacts. Zero value disables WIP pacts. Only takes
+	//effect when pacts are sourced via PactBroker.
+	IncludeWipPactsSince time.Time
+
+	//Parallelism bounds how many interactions are verified concurrently. Interactions are
+	//grouped by provider state first: only states with no setup/teardown action, or states
+	//explicitly marked safe with ProviderStateIsolation, are spread across this pool: every
+	//other state's interactions still run one at a time, in order, within their own group.
+	//Defaults to 1, which preserves the old fully-serial behaviour. Verification also falls
+	//back to fully serial, regardless of this value, when the setup/teardown Actions passed
+	//to NewPactFileVerifier are non-nil: they bracket every interaction, so they can't be
+	//serialized against the request they wrap without the whole run being serial too.
+	Parallelism int
+}
+
+//DefaultVerifierConfig is used when a nil config is passed to NewPactFileVerifier
+var DefaultVerifierConfig = &VerfierConfig{
+	Logger:      log.New(os.Stdout, "pact: ", log.LstdFlags),
+	Parallelism: 1,
+}
+
+//PactUriConfig configures how a pact file is fetched when sourced via PactUri
+type PactUriConfig struct {
+	Username string
+	Password string
+
+	//Cache controls retry/backoff and on-disk caching for web-uri pacts. A nil value falls
+	//back to DefaultCacheConfig. Has no effect when PactUri is given a local file path.
+	Cache *CacheConfig
+}
+
+//DefaultPactUriConfig is used when a nil config is passed to PactUri
+var DefaultPactUriConfig = &PactUriConfig{Cache: DefaultCacheConfig}
+
+//CacheConfig controls retry/backoff and on-disk caching of web-uri pact files.
+//See io.CacheConfig for field documentation.
+type CacheConfig = io.CacheConfig
+
+//DefaultCacheConfig is used when a nil config is assigned to PactUriConfig.Cache
+var DefaultCacheConfig = io.DefaultCacheConfig
+
+//BrokerConfig configures how pacts are sourced from, and verification results published
+//to, a Pact Broker. See broker.BrokerConfig for field documentation.
+type BrokerConfig = broker.BrokerConfig
+
+//ConsumerVersionSelector narrows which consumer pact versions are fetched from the broker.
+//See broker.ConsumerVersionSelector for field documentation.
+type ConsumerVersionSelector = broker.ConsumerVersionSelector
+
+//DefaultBrokerConfig is used when a nil config is passed to PactBroker. It fetches only the
+//latest pact for each consumer and publishes verification results back to the broker.
+var DefaultBrokerConfig = &BrokerConfig{
+	Selectors:      []ConsumerVersionSelector{{Latest: true}},
+	PublishResults: true,
+}
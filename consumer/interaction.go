@@ -0,0 +1,44 @@
+package consumer
+
+//Interaction represents a single recorded request/response exchange between a consumer and provider
+type Interaction struct {
+	Description    string          `json:"description"`
+	State          string          `json:"providerState,omitempty"`
+	ProviderStates []ProviderState `json:"providerStates,omitempty"`
+	Request        *Request        `json:"request"`
+	Response       *Response       `json:"response"`
+}
+
+//StateName returns the provider state this interaction requires, preferring the v3
+//"providerStates" array (only its first entry is supported) and falling back to the
+//older singular "providerState" field
+func (i *Interaction) StateName() string {
+	if len(i.ProviderStates) > 0 {
+		return i.ProviderStates[0].Name
+	}
+	return i.State
+}
+
+//StateParams returns the params associated with the interaction's provider state, if any
+func (i *Interaction) StateParams() map[string]interface{} {
+	if len(i.ProviderStates) > 0 {
+		return i.ProviderStates[0].Params
+	}
+	return nil
+}
+
+//Request is the expected request half of an Interaction
+type Request struct {
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Query   string            `json:"query,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    interface{}       `json:"body,omitempty"`
+}
+
+//Response is the expected response half of an Interaction
+type Response struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    interface{}       `json:"body,omitempty"`
+}
@@ -0,0 +1,26 @@
+package consumer
+
+//Message represents a single asynchronous message entry in a v3+ pact file
+type Message struct {
+	Description    string            `json:"description"`
+	ProviderState  string            `json:"providerState,omitempty"`
+	ProviderStates []ProviderState   `json:"providerStates,omitempty"`
+	Metadata       map[string]string `json:"metaData"`
+	Contents       interface{}       `json:"contents"`
+}
+
+//ProviderState is a single named provider state, as used by the v3 "providerStates" array
+type ProviderState struct {
+	Name   string                 `json:"name"`
+	Params map[string]interface{} `json:"params,omitempty"`
+}
+
+//State returns the provider state this message was recorded against, preferring the v3
+//"providerStates" array (only its first entry is supported) and falling back to the
+//older singular "providerState" field
+func (m *Message) State() string {
+	if len(m.ProviderStates) > 0 {
+		return m.ProviderStates[0].Name
+	}
+	return m.ProviderState
+}
@@ -0,0 +1,120 @@
+package io
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+//CacheConfig controls on-disk caching and retry/backoff behaviour for PactWebReader
+type CacheConfig struct {
+	//Dir is the directory pact files are cached in, keyed by request uri and ETag. Caching
+	//is disabled when Dir is empty, but retry/backoff still applies.
+	Dir string
+
+	//MaxAttempts bounds the total number of attempts, the first included, made while transient
+	//5xx or network errors occur. Values below 1 are treated as 1, i.e. no retry.
+	MaxAttempts int
+
+	//InitialDelay is the backoff before the first retry, doubling on each subsequent attempt
+	//up to MaxDelay.
+	InitialDelay time.Duration
+
+	//MaxDelay caps the backoff delay between retries.
+	MaxDelay time.Duration
+
+	//Jitter adds up to this much additional random delay to each retry, so concurrent CI jobs
+	//sharing a broker/provider don't all retry in lockstep.
+	Jitter time.Duration
+}
+
+//DefaultCacheConfig retries transient errors a handful of times but does not cache to disk
+var DefaultCacheConfig = &CacheConfig{
+	MaxAttempts:  3,
+	InitialDelay: 200 * time.Millisecond,
+	MaxDelay:     5 * time.Second,
+	Jitter:       100 * time.Millisecond,
+}
+
+//cacheEntry is what's persisted to disk for a single cached pact file
+type cacheEntry struct {
+	ETag string `json:"etag"`
+	Body []byte `json:"body"`
+}
+
+func cacheKey(uri string) string {
+	sum := sha256.Sum256([]byte(uri))
+	return hex.EncodeToString(sum[:])
+}
+
+func cachePaths(dir, uri string) (entryPath, lockPath string) {
+	key := cacheKey(uri)
+	return filepath.Join(dir, key+".json"), filepath.Join(dir, key+".lock")
+}
+
+//readCacheEntry acquires the advisory lock guarding uri's cache entry in dir and reads it, if
+//present. The caller must release the returned lock once it is done reading and writing.
+func readCacheEntry(dir, uri string) (*cacheEntry, *fileLock, error) {
+	entryPath, lockPath := cachePaths(dir, uri)
+
+	lock, err := lockFile(lockPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body, err := ioutil.ReadFile(entryPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, lock, nil
+		}
+		lock.Unlock()
+		return nil, nil, err
+	}
+
+	entry := &cacheEntry{}
+	if err := json.Unmarshal(body, entry); err != nil {
+		lock.Unlock()
+		return nil, nil, err
+	}
+	return entry, lock, nil
+}
+
+//writeCacheEntry writes entry for uri's cache slot in dir. The caller must already hold the
+//lock returned by readCacheEntry.
+func writeCacheEntry(dir, uri string, entry *cacheEntry) error {
+	entryPath, _ := cachePaths(dir, uri)
+	body, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(entryPath, body, 0644)
+}
+
+//removeLockFile deletes uri's lock file in dir so a crashed or backoff-exhausted process
+//doesn't wedge future runs sharing the same cache directory
+func removeLockFile(dir, uri string) {
+	_, lockPath := cachePaths(dir, uri)
+	os.Remove(lockPath)
+}
+
+//backoff sleeps for the delay appropriate to the given 0-based retry attempt, honouring
+//config's initial delay, max delay and jitter
+func backoff(config *CacheConfig, attempt int) {
+	delay := config.InitialDelay
+	for i := 0; i < attempt; i++ {
+		delay *= 2
+		if delay >= config.MaxDelay {
+			delay = config.MaxDelay
+			break
+		}
+	}
+	if config.Jitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(config.Jitter)))
+	}
+	time.Sleep(delay)
+}
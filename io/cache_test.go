@@ -0,0 +1,78 @@
+package io
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+const testPactJSON = `{"consumer":{"name":"c"},"provider":{"name":"p"},"interactions":[{"description":"d","request":{"method":"GET","path":"/"},"response":{"status":200}}]}`
+
+func testCacheConfig(dir string) *CacheConfig {
+	return &CacheConfig{Dir: dir, MaxAttempts: 1, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond}
+}
+
+//TestPactWebReader_CacheRoundTripAnd304 checks that a first fetch populates the on-disk cache
+//and that a later reader for the same uri revalidates with If-None-Match and reuses the cached
+//body on a 304 instead of re-downloading it.
+func TestPactWebReader_CacheRoundTripAnd304(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(testPactJSON))
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "pact-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	config := testCacheConfig(dir)
+
+	f, err := NewPactWebReader(server.URL, "", "", config).Read()
+	if err != nil {
+		t.Fatalf("first read: %v", err)
+	}
+	if f.Consumer["name"] != "c" {
+		t.Fatalf("expected consumer name %q, got %q", "c", f.Consumer["name"])
+	}
+	if requests != 1 {
+		t.Fatalf("expected 1 request after the first read, got %d", requests)
+	}
+
+	f, err = NewPactWebReader(server.URL, "", "", config).Read()
+	if err != nil {
+		t.Fatalf("second read: %v", err)
+	}
+	if requests != 2 {
+		t.Fatalf("expected the second read to revalidate with the server, got %d requests", requests)
+	}
+	if f.Consumer["name"] != "c" {
+		t.Fatalf("expected the 304 to be served from the cached body, got consumer name %q", f.Consumer["name"])
+	}
+}
+
+//TestPactWebReader_NotModifiedWithoutCacheEntryErrors checks that a 304 response is treated as
+//an error rather than panicking when there is no cached body to revalidate against.
+func TestPactWebReader_NotModifiedWithoutCacheEntryErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer server.Close()
+
+	_, err := NewPactWebReader(server.URL, "", "", &CacheConfig{MaxAttempts: 1}).Read()
+	if err == nil {
+		t.Fatal("expected an error when the server returns 304 with no cached entry to revalidate")
+	}
+}
@@ -0,0 +1,36 @@
+//go:build !windows
+// +build !windows
+
+package io
+
+import (
+	"os"
+	"syscall"
+)
+
+//fileLock is an OS-level advisory lock over a single file, used to serialise pact file cache
+//reads/writes across concurrent verifier processes sharing a cache directory
+type fileLock struct {
+	f *os.File
+}
+
+//lockFile acquires an exclusive advisory lock on path, creating it first if necessary. The
+//returned fileLock must be released with Unlock once the caller is done with the cache entry.
+func lockFile(path string) (*fileLock, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &fileLock{f: f}, nil
+}
+
+//Unlock releases the advisory lock and closes the underlying file handle
+func (l *fileLock) Unlock() error {
+	defer l.f.Close()
+	return syscall.Flock(int(l.f.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,37 @@
+//go:build windows
+// +build windows
+
+package io
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+//fileLock is an OS-level advisory lock over a single file, used to serialise pact file cache
+//reads/writes across concurrent verifier processes sharing a cache directory
+type fileLock struct {
+	f *os.File
+}
+
+//lockFile acquires an exclusive advisory lock on path, creating it first if necessary. The
+//returned fileLock must be released with Unlock once the caller is done with the cache entry.
+func lockFile(path string) (*fileLock, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := windows.LockFileEx(windows.Handle(f.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK, 0, 1, 0, new(windows.Overlapped)); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &fileLock{f: f}, nil
+}
+
+//Unlock releases the advisory lock and closes the underlying file handle
+func (l *fileLock) Unlock() error {
+	defer l.f.Close()
+	return windows.UnlockFileEx(windows.Handle(l.f.Fd()), 0, 1, 0, new(windows.Overlapped))
+}
@@ -0,0 +1,195 @@
+package io
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/SEEK-Jobs/pact-go/consumer"
+)
+
+//PactFile is the deserialised contents of a pact file exchanged between a consumer and provider.
+//A v3+ message pact populates Messages instead of (or as well as) Interactions.
+type PactFile struct {
+	Consumer     map[string]string       `json:"consumer"`
+	Provider     map[string]string       `json:"provider"`
+	Interactions []*consumer.Interaction `json:"interactions"`
+	Messages     []*consumer.Message     `json:"messages"`
+	Metadata     map[string]interface{}  `json:"metadata"`
+}
+
+var errNoInteractionsOrMessages = errors.New("The pact file does not contain any interactions or messages.")
+
+//Validate checks that the pact file is well formed enough to be verified
+func (f *PactFile) Validate() error {
+	if len(f.Interactions) == 0 && len(f.Messages) == 0 {
+		return errNoInteractionsOrMessages
+	}
+	return nil
+}
+
+//PactReader reads a pact file from some source, e.g. the local filesystem or a web uri
+type PactReader interface {
+	Read() (*PactFile, error)
+}
+
+//IsWebUri returns true if uri is a http(s) address rather than a local file path
+func IsWebUri(uri string) bool {
+	return strings.HasPrefix(uri, "http://") || strings.HasPrefix(uri, "https://")
+}
+
+//PactWebReader reads a pact file over http(s), optionally authenticating with basic auth.
+//Transient failures are retried with exponential backoff, and successful fetches are cached
+//to disk and revalidated with a conditional GET, per config.
+type PactWebReader struct {
+	uri      string
+	username string
+	password string
+	cache    *CacheConfig
+}
+
+//NewPactWebReader creates a reader that fetches the pact file located at uri. A nil config
+//falls back to DefaultCacheConfig.
+func NewPactWebReader(uri, username, password string, config *CacheConfig) *PactWebReader {
+	if config == nil {
+		config = DefaultCacheConfig
+	}
+	return &PactWebReader{uri: uri, username: username, password: password, cache: config}
+}
+
+//transientError marks an error as worth retrying, as opposed to e.g. a 4xx response or a
+//malformed cache entry
+type transientError struct{ error }
+
+//Read fetches and deserialises the pact file, retrying transient errors with backoff and,
+//when r.cache.Dir is set, serving a cached copy on a 304 or caching a fresh 200 response
+func (r *PactWebReader) Read() (*PactFile, error) {
+	var cached *cacheEntry
+	var lock *fileLock
+
+	if r.cache.Dir != "" {
+		entry, l, err := readCacheEntry(r.cache.Dir, r.uri)
+		if err != nil {
+			return nil, err
+		}
+		cached, lock = entry, l
+	}
+
+	//unlock releases the lock, if held, exactly once. It must run before removeLockFile below:
+	//removing the path out from under a still-held flock would let a waiter on the old inode
+	//and a new process that recreates the path both believe they hold "the lock" at once.
+	unlock := func() {
+		if lock != nil {
+			lock.Unlock()
+			lock = nil
+		}
+	}
+	defer unlock()
+
+	maxAttempts := r.cache.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if attempt > 0 {
+			backoff(r.cache, attempt-1)
+		}
+
+		body, etag, notModified, err := r.fetch(cached)
+		if err == nil {
+			if notModified {
+				return unmarshalPactFile(cached.Body)
+			}
+			if r.cache.Dir != "" {
+				if err := writeCacheEntry(r.cache.Dir, r.uri, &cacheEntry{ETag: etag, Body: body}); err != nil {
+					return nil, err
+				}
+			}
+			return unmarshalPactFile(body)
+		}
+
+		lastErr = err
+		if _, transient := err.(transientError); !transient {
+			return nil, lastErr
+		}
+	}
+
+	//the backoff budget is exhausted; unlock before removing the lock file so nothing else
+	//can be holding it at removal time, then drop it so a crash here doesn't wedge future runs
+	unlock()
+	if r.cache.Dir != "" {
+		removeLockFile(r.cache.Dir, r.uri)
+	}
+	return nil, lastErr
+}
+
+//fetch performs a single attempt at retrieving the pact file, sending a conditional
+//If-None-Match request when cached has an ETag to revalidate
+func (r *PactWebReader) fetch(cached *cacheEntry) (body []byte, etag string, notModified bool, err error) {
+	req, err := http.NewRequest(http.MethodGet, r.uri, nil)
+	if err != nil {
+		return nil, "", false, err
+	}
+	if r.username != "" {
+		req.SetBasicAuth(r.username, r.password)
+	}
+	if cached != nil && cached.ETag != "" {
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, "", false, transientError{err}
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified:
+		if cached == nil {
+			return nil, "", false, errors.New("received a 304 Not Modified response but no cached pact file is available to revalidate")
+		}
+		return nil, cached.ETag, true, nil
+	case resp.StatusCode == http.StatusOK:
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, "", false, err
+		}
+		return body, resp.Header.Get("ETag"), false, nil
+	case resp.StatusCode >= 500:
+		return nil, "", false, transientError{fmt.Errorf("failed to fetch the pact file, received status %s", resp.Status)}
+	default:
+		return nil, "", false, errors.New("Failed to fetch the pact file, received status " + resp.Status)
+	}
+}
+
+//PactFileReader reads a pact file from the local filesystem
+type PactFileReader struct {
+	path string
+}
+
+//NewPactFileReader creates a reader that reads the pact file located at path
+func NewPactFileReader(path string) *PactFileReader {
+	return &PactFileReader{path: path}
+}
+
+//Read reads and deserialises the pact file
+func (r *PactFileReader) Read() (*PactFile, error) {
+	body, err := ioutil.ReadFile(r.path)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalPactFile(body)
+}
+
+func unmarshalPactFile(body []byte) (*PactFile, error) {
+	f := &PactFile{}
+	if err := json.Unmarshal(body, f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
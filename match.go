@@ -0,0 +1,24 @@
+package pact
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+//MatchBody reports whether actual matches the shape and values of expected, the way a pact
+//file's JSON body/contents is compared against what the provider/handler actually returned.
+//actual is round-tripped through JSON first so map[string]interface{} comparisons line up
+//with the types produced by unmarshalling a pact file.
+func MatchBody(expected, actual interface{}) (bool, error) {
+	body, err := json.Marshal(actual)
+	if err != nil {
+		return false, err
+	}
+
+	var roundTripped interface{}
+	if err := json.Unmarshal(body, &roundTripped); err != nil {
+		return false, err
+	}
+
+	return reflect.DeepEqual(expected, roundTripped), nil
+}
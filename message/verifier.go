@@ -0,0 +1,164 @@
+//Package message verifies the asynchronous "messages" entries of a v3+ pact file, mirroring
+//the HTTP Verifier in the parent pact package but invoking a registered handler function
+//instead of making a request to a running provider.
+package message
+
+import (
+	"errors"
+
+	pact "github.com/SEEK-Jobs/pact-go"
+	"github.com/SEEK-Jobs/pact-go/consumer"
+	"github.com/SEEK-Jobs/pact-go/io"
+)
+
+//MessageHandlerFunc produces the actual content of a described message, given its metadata.
+//The returned value is marshalled to JSON and compared against the pact's recorded contents.
+type MessageHandlerFunc func(metadata map[string]string) (interface{}, error)
+
+//MessageVerifier verifies the messages of a pact file against handlers registered by the provider
+type MessageVerifier interface {
+	HonoursPactWith(consumerName string) MessageVerifier
+	PactUri(uri string, config *pact.PactUriConfig) MessageVerifier
+	ProviderState(state string, setup, teardown pact.Action) MessageVerifier
+	MessageHandler(description string, handler MessageHandlerFunc) MessageVerifier
+	Verify() error
+}
+
+type messagePactVerifier struct {
+	consumer      string
+	pactUri       string
+	pactUriConfig *pact.PactUriConfig
+	handlers      map[string]MessageHandlerFunc
+	stateActions  pact.StateActions
+	config        *pact.VerfierConfig
+}
+
+//NewMessagePactVerifier creates a new verifier for a pact file's asynchronous messages
+func NewMessagePactVerifier(config *pact.VerfierConfig) MessageVerifier {
+	if config == nil {
+		config = pact.DefaultVerifierConfig
+	}
+
+	return &messagePactVerifier{
+		handlers:     make(map[string]MessageHandlerFunc),
+		stateActions: make(pact.StateActions),
+		config:       config,
+	}
+}
+
+var (
+	errEmptyConsumer     = errors.New("Consumer name cannot be empty, please provide a valid value using HonoursPactWith function.")
+	errNoHandler         = errors.New("No message handler has been registered for this description, please register one using MessageHandler function.")
+	errVerficationFailed = errors.New("Failed to verify the message pact, please see the log for more details.")
+)
+
+//HonoursPactWith consumer with which pact needs to be honoured
+func (v *messagePactVerifier) HonoursPactWith(consumerName string) MessageVerifier {
+	v.consumer = consumerName
+	return v
+}
+
+//PactUri sets the uri to get the pact file
+func (v *messagePactVerifier) PactUri(uri string, config *pact.PactUriConfig) MessageVerifier {
+	if config == nil {
+		config = pact.DefaultPactUriConfig
+	}
+	v.pactUriConfig = config
+	v.pactUri = uri
+	return v
+}
+
+//ProviderState sets the setup and teardown action to be executed before a message with specific
+//state gets verified. States are held in this verifier's own pact.StateActions map, the same
+//type an HTTP Verifier uses; they are not shared with it, so a state used by both is
+//registered with each.
+func (v *messagePactVerifier) ProviderState(state string, setup, teardown pact.Action) MessageVerifier {
+	if state != "" {
+		v.stateActions[state] = &pact.StateAction{Setup: setup, Teardown: teardown}
+	}
+	return v
+}
+
+//MessageHandler registers the handler that produces the actual content for messages with
+//the given description
+func (v *messagePactVerifier) MessageHandler(description string, handler MessageHandlerFunc) MessageVerifier {
+	v.handlers[description] = handler
+	return v
+}
+
+//Verify verifies every message in the pact file against its registered handler
+func (v *messagePactVerifier) Verify() error {
+	if v.consumer == "" {
+		return errEmptyConsumer
+	}
+
+	f, err := v.getPactFile()
+	if err != nil {
+		return err
+	}
+
+	ok := true
+	for _, m := range f.Messages {
+		sa := v.stateActions[m.State()]
+
+		if sa != nil && sa.Setup != nil {
+			if err := sa.Setup(); err != nil {
+				return err
+			}
+		}
+
+		if err := v.validateMessage(m); err != nil {
+			v.config.Logger.Printf("message %q failed verification: %v", m.Description, err)
+			ok = false
+		}
+
+		if sa != nil && sa.Teardown != nil {
+			if err := sa.Teardown(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !ok {
+		return errVerficationFailed
+	}
+	return nil
+}
+
+func (v *messagePactVerifier) validateMessage(m *consumer.Message) error {
+	handler, found := v.handlers[m.Description]
+	if !found {
+		return errNoHandler
+	}
+
+	actual, err := handler(m.Metadata)
+	if err != nil {
+		return err
+	}
+
+	if matched, err := pact.MatchBody(m.Contents, actual); err != nil {
+		return err
+	} else if !matched {
+		return errors.New("actual message contents did not match the pact")
+	}
+	return nil
+}
+
+func (v *messagePactVerifier) getPactFile() (*io.PactFile, error) {
+	var r io.PactReader
+	if io.IsWebUri(v.pactUri) {
+		r = io.NewPactWebReader(v.pactUri, v.pactUriConfig.Username, v.pactUriConfig.Password, v.pactUriConfig.Cache)
+	} else {
+		r = io.NewPactFileReader(v.pactUri)
+	}
+
+	f, err := r.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
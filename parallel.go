@@ -0,0 +1,85 @@
+package pact
+
+import (
+	"sync"
+
+	"github.com/SEEK-Jobs/pact-go/io"
+)
+
+//stateGroup is every interaction in a pact file that shares a provider state, in file order
+type stateGroup struct {
+	state   string
+	indices []int
+}
+
+func groupInteractionsByState(f *io.PactFile) []*stateGroup {
+	var groups []*stateGroup
+	byState := make(map[string]*stateGroup)
+
+	for idx, i := range f.Interactions {
+		state := i.StateName()
+		g, ok := byState[state]
+		if !ok {
+			g = &stateGroup{state: state}
+			byState[state] = g
+			groups = append(groups, g)
+		}
+		g.indices = append(g.indices, idx)
+	}
+	return groups
+}
+
+//verificationTask is a unit of work handed to a worker: a single interaction when its state
+//is safe to run concurrently, or a whole state group when it must run serially
+type verificationTask struct {
+	state   string
+	sa      *StateAction
+	indices []int
+}
+
+//isParallelSafe reports whether state's interactions may run concurrently with each other.
+//A state with no setup/teardown action has nothing to race on, so it's safe by default;
+//otherwise the caller must have opted in via ProviderStateIsolation.
+func isParallelSafe(state string, sa *StateAction, isolated map[string]bool) bool {
+	if sa == nil || (sa.Setup == nil && sa.Teardown == nil) {
+		return true
+	}
+	return isolated[state]
+}
+
+//buildVerificationTasks splits f's interactions into the ones safe to verify concurrently
+//(fanned out to one task per interaction) and the state groups that are not: those keep their
+//interactions together as a single task, and every one of those tasks must still run serially
+//with respect to every other one, since two different stateful provider states (e.g.
+//"balance=100" and "balance=0" for the same account) can race each other just as easily as two
+//interactions within the same state can.
+func buildVerificationTasks(f *io.PactFile, stateActions StateActions, isolated map[string]bool) (safeTasks, unsafeTasks []*verificationTask) {
+	for _, g := range groupInteractionsByState(f) {
+		sa := stateActions[g.state]
+		if isParallelSafe(g.state, sa, isolated) {
+			for _, idx := range g.indices {
+				safeTasks = append(safeTasks, &verificationTask{state: g.state, sa: sa, indices: []int{idx}})
+			}
+			continue
+		}
+		unsafeTasks = append(unsafeTasks, &verificationTask{state: g.state, sa: sa, indices: g.indices})
+	}
+	return safeTasks, unsafeTasks
+}
+
+//safeLogger wraps a Logger with a mutex so interactions verified concurrently don't interleave
+//their log output
+type safeLogger struct {
+	mu     sync.Mutex
+	logger Logger
+}
+
+func newSafeLogger(logger Logger) *safeLogger {
+	return &safeLogger{logger: logger}
+}
+
+func (s *safeLogger) Printf(format string, args ...interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logger.Printf(format, args...)
+}
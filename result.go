@@ -0,0 +1,37 @@
+package pact
+
+import "errors"
+
+//ErrVerificationFailedNonPending is returned by VerifyDetailed(), and by Verify() when pacts
+//are sourced via PactBroker, when one or more interactions failed verification that did not
+//come from a pending or WIP pact. Verify() on a pact sourced via PactUri returns
+//errVerficationFailed instead. Unlike
+//errVerficationFailed, a caller can rely on this sentinel to tell a "real" failure apart
+//from ones that were merely logged and reported because they were still pending.
+var ErrVerificationFailedNonPending = errors.New("Failed to verify one or more non-pending interactions, please see the log for more details.")
+
+//InteractionResult is the verification outcome of a single interaction
+type InteractionResult struct {
+	Description string
+	State       string
+	//Pending is true when the interaction came from a pact the broker marked as pending or
+	//work-in-progress, meaning its failure must be reported but must not fail the build
+	Pending bool
+	Success bool
+	Err     error
+}
+
+//VerificationResult is the structured, per-interaction outcome of a Verify() run
+type VerificationResult struct {
+	Interactions []*InteractionResult
+}
+
+//Success reports whether every non-pending interaction passed verification
+func (r *VerificationResult) Success() bool {
+	for _, i := range r.Interactions {
+		if !i.Success && !i.Pending {
+			return false
+		}
+	}
+	return true
+}
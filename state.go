@@ -0,0 +1,13 @@
+package pact
+
+//StateAction is the setup/teardown pair run before/after an interaction or message that
+//declares a matching provider state
+type StateAction struct {
+	Setup    Action
+	Teardown Action
+}
+
+//StateActions indexes StateAction by provider state name. It is exported so that the HTTP
+//and message verifiers use the same type for their registered provider states; each verifier
+//holds its own StateActions, so a state is registered separately with each one.
+type StateActions map[string]*StateAction
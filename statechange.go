@@ -0,0 +1,102 @@
+package pact
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+//StateChangeConfig configures requests made to a provider's out-of-process state-change endpoint
+type StateChangeConfig struct {
+	//Teardown controls whether a teardown request is sent after the interaction, in addition
+	//to the setup request sent before it. DefaultStateChangeConfig, used for a nil config, sets
+	//it to true; the zero value false sends no teardown request.
+	Teardown bool
+
+	//ParamsInQueryString sends the provider state's params, together with the state name and
+	//action, as query string parameters with no request body, instead of as a JSON request body.
+	ParamsInQueryString bool
+
+	//Headers are added to every state change request, e.g. for authentication
+	Headers map[string]string
+}
+
+//DefaultStateChangeConfig is used when a nil config is passed to ProviderStateURL
+var DefaultStateChangeConfig = &StateChangeConfig{Teardown: true}
+
+type stateChangeRequest struct {
+	State  string                 `json:"state"`
+	Params map[string]interface{} `json:"params,omitempty"`
+	Action string                 `json:"action"`
+}
+
+//stateChangeClient posts setup/teardown requests to a provider's state-change endpoint. It is
+//used as a fallback when no in-process ProviderState callback has been registered for a state.
+type stateChangeClient struct {
+	url    *url.URL
+	config *StateChangeConfig
+	http   *http.Client
+}
+
+func newStateChangeClient(u *url.URL, config *StateChangeConfig) *stateChangeClient {
+	return &stateChangeClient{url: u, config: config, http: http.DefaultClient}
+}
+
+//Setup notifies the provider that state is about to be verified
+func (c *stateChangeClient) Setup(state string, params map[string]interface{}) error {
+	return c.send(state, params, "setup")
+}
+
+//Teardown notifies the provider that state has finished being verified. A no-op if the
+//config disabled teardown requests.
+func (c *stateChangeClient) Teardown(state string, params map[string]interface{}) error {
+	if !c.config.Teardown {
+		return nil
+	}
+	return c.send(state, params, "teardown")
+}
+
+func (c *stateChangeClient) send(state string, params map[string]interface{}, action string) error {
+	u := *c.url
+	var body []byte
+
+	if c.config.ParamsInQueryString {
+		q := u.Query()
+		for k, v := range params {
+			q.Set(k, fmt.Sprintf("%v", v))
+		}
+		q.Set("state", state)
+		q.Set("action", action)
+		u.RawQuery = q.Encode()
+	} else {
+		b, err := json.Marshal(stateChangeRequest{State: state, Params: params, Action: action})
+		if err != nil {
+			return err
+		}
+		body = b
+	}
+
+	req, err := http.NewRequest(http.MethodPost, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if !c.config.ParamsInQueryString {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range c.config.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("provider state change endpoint returned unexpected status %s for state %q", resp.Status, state)
+	}
+	return nil
+}
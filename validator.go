@@ -0,0 +1,351 @@
+package pact
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/SEEK-Jobs/pact-go/consumer"
+	"github.com/SEEK-Jobs/pact-go/io"
+)
+
+var errProviderServiceNotConfigured = fmt.Errorf("Provider service has not been configured, please call ServiceProvider.")
+
+//consumerValidator replays the interactions of a pact file against a running provider
+type consumerValidator interface {
+	CanValidate() error
+	ProviderService(c *http.Client, u *url.URL)
+	StateChangeEndpoint(u *url.URL, config *StateChangeConfig)
+	SetParallelism(n int)
+	MarkStateParallelSafe(state string)
+	Validate(f *io.PactFile, stateActions StateActions) (bool, error)
+	ValidateDetailed(f *io.PactFile, stateActions StateActions, pending bool) ([]*InteractionResult, error)
+}
+
+type validator struct {
+	setup          Action
+	teardown       Action
+	logger         Logger
+	client         *http.Client
+	baseURI        *url.URL
+	stateChange    *stateChangeClient
+	parallelism    int
+	isolatedStates map[string]bool
+}
+
+func newConsumerValidator(setup, teardown Action, logger Logger) consumerValidator {
+	return &validator{
+		setup:          setup,
+		teardown:       teardown,
+		logger:         newSafeLogger(logger),
+		parallelism:    1,
+		isolatedStates: make(map[string]bool),
+	}
+}
+
+//SetParallelism bounds how many interactions are verified concurrently. n < 1 is treated as 1.
+func (v *validator) SetParallelism(n int) {
+	if n < 1 {
+		n = 1
+	}
+	v.parallelism = n
+}
+
+//MarkStateParallelSafe opts a provider state with a registered setup/teardown action into
+//concurrent verification, e.g. because its fixtures are read-only
+func (v *validator) MarkStateParallelSafe(state string) {
+	v.isolatedStates[state] = true
+}
+
+//ProviderService records the http client and base uri used to reach the provider
+func (v *validator) ProviderService(c *http.Client, u *url.URL) {
+	v.client = c
+	v.baseURI = u
+}
+
+//StateChangeEndpoint records the out-of-process state-change endpoint to fall back to for
+//provider states that have no in-process ProviderState callback registered
+func (v *validator) StateChangeEndpoint(u *url.URL, config *StateChangeConfig) {
+	v.stateChange = newStateChangeClient(u, config)
+}
+
+//CanValidate checks the validator has everything it needs before validating interactions
+func (v *validator) CanValidate() error {
+	if v.client == nil || v.baseURI == nil {
+		return errProviderServiceNotConfigured
+	}
+	return nil
+}
+
+//Validate replays each interaction in f against the provider, running the matching state's setup
+//and teardown actions (if any) around it
+func (v *validator) Validate(f *io.PactFile, stateActions StateActions) (bool, error) {
+	results, err := v.ValidateDetailed(f, stateActions, false)
+	if err != nil {
+		return false, err
+	}
+
+	ok := true
+	for _, r := range results {
+		if !r.Success && !r.Pending {
+			ok = false
+		}
+	}
+	return ok, nil
+}
+
+//ValidateDetailed is like Validate but returns the outcome of every interaction rather than
+//collapsing them into a single bool. pending marks every interaction as belonging to a pending
+//or WIP pact, so a failure is recorded but does not mark the overall run as failed.
+//
+//At the default v.parallelism of 1, and whenever a global setup/teardown action is configured
+//(it brackets every interaction, so it can't be serialized against the request it wraps
+//without the whole run being serial), every interaction runs one at a time, in file order.
+//Otherwise interactions are grouped by provider state: groups with no setup/teardown action,
+//or marked safe with MarkStateParallelSafe, are fanned out one interaction at a time across a
+//pool of workers. Every other group must not race another stateful group any more than it may
+//race itself, so those run one at a time on a single dedicated lane alongside the pool - with
+//the pool sized down by one worker so total in-flight work never exceeds v.parallelism.
+func (v *validator) ValidateDetailed(f *io.PactFile, stateActions StateActions, pending bool) ([]*InteractionResult, error) {
+	if v.parallelism <= 1 || v.setup != nil || v.teardown != nil {
+		return v.validateSerial(f, stateActions, pending)
+	}
+	return v.validateParallel(f, stateActions, pending)
+}
+
+//validateSerial runs every interaction one at a time, in file order
+func (v *validator) validateSerial(f *io.PactFile, stateActions StateActions, pending bool) ([]*InteractionResult, error) {
+	results := make([]*InteractionResult, len(f.Interactions))
+	for idx, i := range f.Interactions {
+		state := i.StateName()
+		r, err := v.validateOne(i, state, stateActions[state], pending)
+		if err != nil {
+			return nil, err
+		}
+		results[idx] = r
+	}
+	return results, nil
+}
+
+//validateParallel fans parallel-safe interactions out across a bounded worker pool while
+//running every unsafe state group serially, on its own lane, with respect to every other one.
+//Only called when v.parallelism > 1 and no global setup/teardown is configured.
+func (v *validator) validateParallel(f *io.PactFile, stateActions StateActions, pending bool) ([]*InteractionResult, error) {
+	safeTasks, unsafeTasks := buildVerificationTasks(f, stateActions, v.isolatedStates)
+
+	//the unsafe lane counts against v.parallelism's bound too, so the pool only gets the
+	//remaining workers when there's actually an unsafe lane running alongside it
+	poolSize := v.parallelism
+	if len(unsafeTasks) > 0 {
+		poolSize--
+	}
+	if poolSize < 1 {
+		poolSize = 1
+	}
+	if poolSize > len(safeTasks) {
+		poolSize = len(safeTasks)
+	}
+	if poolSize < 1 {
+		poolSize = 1
+	}
+
+	results := make([]*InteractionResult, len(f.Interactions))
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+
+	record := func(idx int, r *InteractionResult, err error) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			return false
+		}
+		results[idx] = r
+		return true
+	}
+
+	//failed reports whether any task anywhere has already errored, so a lane can stop pulling
+	//further tasks instead of continuing to hit the provider once the whole call is doomed to
+	//return an error and discard every result
+	failed := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return firstErr != nil
+	}
+
+	if len(unsafeTasks) > 0 {
+		//the unsafe lane: every stateful group not marked parallel-safe, run one at a time so
+		//no two of them can ever race each other
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, t := range unsafeTasks {
+				if failed() {
+					return
+				}
+				for _, idx := range t.indices {
+					r, err := v.validateOne(f.Interactions[idx], t.state, t.sa, pending)
+					if !record(idx, r, err) {
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	taskCh := make(chan *verificationTask)
+	for n := 0; n < poolSize; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range taskCh {
+				if failed() {
+					continue
+				}
+				for _, idx := range t.indices {
+					r, err := v.validateOne(f.Interactions[idx], t.state, t.sa, pending)
+					if !record(idx, r, err) {
+						break
+					}
+				}
+			}
+		}()
+	}
+
+	for _, t := range safeTasks {
+		taskCh <- t
+	}
+	close(taskCh)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return results, nil
+}
+
+//validateOne runs a single interaction's setup, request/response verification and teardown
+func (v *validator) validateOne(i *consumer.Interaction, state string, sa *StateAction, pending bool) (*InteractionResult, error) {
+	if err := v.runSetup(state, sa, i.StateParams()); err != nil {
+		return nil, err
+	}
+
+	r := &InteractionResult{Description: i.Description, State: state, Pending: pending}
+	if err := v.validateInteraction(i); err != nil {
+		v.logger.Printf("interaction %q failed verification: %v", i.Description, err)
+		r.Err = err
+	} else {
+		r.Success = true
+	}
+
+	if err := v.runTeardown(state, sa, i.StateParams()); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+//runSetup runs the global setup action, then the interaction's provider state setup. A state
+//registered via ProviderState owns both of its phases: if it's registered at all, its Setup
+//callback runs (or nothing does, if Setup is nil) and the state-change endpoint is never
+//consulted for it. The endpoint is only a fallback for states with no ProviderState
+//registration. ValidateDetailed never reaches this concurrently when a global setup/teardown
+//is configured, so no synchronization is needed around v.setup here.
+func (v *validator) runSetup(state string, sa *StateAction, params map[string]interface{}) error {
+	if v.setup != nil {
+		if err := v.setup(); err != nil {
+			return err
+		}
+	}
+	if sa != nil {
+		if sa.Setup != nil {
+			return sa.Setup()
+		}
+		return nil
+	}
+	if v.stateChange != nil && state != "" {
+		return v.stateChange.Setup(state, params)
+	}
+	return nil
+}
+
+//runTeardown is the teardown counterpart to runSetup: the interaction's provider state
+//teardown runs before the global teardown. As in runSetup, a registered state owns both of
+//its phases, so a registered Setup with no Teardown does not fall back to the state-change
+//endpoint, and the global action needs no synchronization for the same reason as runSetup's.
+func (v *validator) runTeardown(state string, sa *StateAction, params map[string]interface{}) error {
+	if sa != nil {
+		if sa.Teardown != nil {
+			if err := sa.Teardown(); err != nil {
+				return err
+			}
+		}
+	} else if v.stateChange != nil && state != "" {
+		if err := v.stateChange.Teardown(state, params); err != nil {
+			return err
+		}
+	}
+	if v.teardown != nil {
+		if err := v.teardown(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *validator) validateInteraction(i *consumer.Interaction) error {
+	u := *v.baseURI
+	u.Path = i.Request.Path
+	u.RawQuery = i.Request.Query
+
+	var body []byte
+	if i.Request.Body != nil {
+		b, err := json.Marshal(i.Request.Body)
+		if err != nil {
+			return err
+		}
+		body = b
+	}
+
+	req, err := http.NewRequest(i.Request.Method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, val := range i.Request.Headers {
+		req.Header.Set(k, val)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if i.Response.Status != 0 && resp.StatusCode != i.Response.Status {
+		return fmt.Errorf("expected status %d but got %d", i.Response.Status, resp.StatusCode)
+	}
+
+	if i.Response.Body != nil {
+		actual, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		var actualBody interface{}
+		if err := json.Unmarshal(actual, &actualBody); err != nil {
+			return err
+		}
+		if matched, err := MatchBody(i.Response.Body, actualBody); err != nil {
+			return err
+		} else if !matched {
+			return fmt.Errorf("response body did not match the pact for %q", i.Description)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,100 @@
+package pact
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/SEEK-Jobs/pact-go/consumer"
+	"github.com/SEEK-Jobs/pact-go/io"
+)
+
+type nullLogger struct{}
+
+func (nullLogger) Printf(format string, args ...interface{}) {}
+
+//TestValidateDetailed_SerialAtDefaultParallelism checks that a pact mixing a stateless
+//interaction with two different stateful provider states runs fully serially, in file order,
+//at the default Parallelism of 1 - the contract documented on VerfierConfig.Parallelism.
+func TestValidateDetailed_SerialAtDefaultParallelism(t *testing.T) {
+	var mu sync.Mutex
+	var order []string
+	recordEvent := func(event string) {
+		mu.Lock()
+		order = append(order, event)
+		mu.Unlock()
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recordEvent("request:" + r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	baseURI, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := newConsumerValidator(nil, nil, nullLogger{})
+	v.ProviderService(server.Client(), baseURI)
+
+	stateActions := StateActions{
+		"balance=100": {
+			Setup:    func() error { recordEvent("setup:balance=100"); return nil },
+			Teardown: func() error { recordEvent("teardown:balance=100"); return nil },
+		},
+		"balance=0": {
+			Setup:    func() error { recordEvent("setup:balance=0"); return nil },
+			Teardown: func() error { recordEvent("teardown:balance=0"); return nil },
+		},
+	}
+
+	f := &io.PactFile{
+		Interactions: []*consumer.Interaction{
+			{
+				Description: "no state",
+				Request:     &consumer.Request{Method: http.MethodGet, Path: "/a"},
+				Response:    &consumer.Response{Status: http.StatusOK},
+			},
+			{
+				Description:    "balance is 100",
+				ProviderStates: []consumer.ProviderState{{Name: "balance=100"}},
+				Request:        &consumer.Request{Method: http.MethodGet, Path: "/b"},
+				Response:       &consumer.Response{Status: http.StatusOK},
+			},
+			{
+				Description:    "balance is 0",
+				ProviderStates: []consumer.ProviderState{{Name: "balance=0"}},
+				Request:        &consumer.Request{Method: http.MethodGet, Path: "/c"},
+				Response:       &consumer.Response{Status: http.StatusOK},
+			},
+		},
+	}
+
+	results, err := v.ValidateDetailed(f, stateActions, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, r := range results {
+		if !r.Success {
+			t.Fatalf("expected interaction %q to succeed, got err %v", r.Description, r.Err)
+		}
+	}
+
+	want := []string{
+		"request:/a",
+		"setup:balance=100", "request:/b", "teardown:balance=100",
+		"setup:balance=0", "request:/c", "teardown:balance=0",
+	}
+	if len(order) != len(want) {
+		t.Fatalf("expected event order %v, got %v", want, order)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("expected event order %v, got %v", want, order)
+		}
+	}
+}
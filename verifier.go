@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/url"
 
+	"github.com/SEEK-Jobs/pact-go/broker"
 	"github.com/SEEK-Jobs/pact-go/consumer"
 	"github.com/SEEK-Jobs/pact-go/io"
 )
@@ -15,23 +16,24 @@ type Verifier interface {
 	ServiceProvider(providerName string, c *http.Client, u *url.URL) Verifier
 	HonoursPactWith(consumerName string) Verifier
 	PactUri(uri string, config *PactUriConfig) Verifier
+	PactBroker(brokerURL string, config *BrokerConfig) Verifier
+	ProviderStateURL(u *url.URL, config *StateChangeConfig) Verifier
+	ProviderStateIsolation(state string) Verifier
 	Verify() error
 	VerifyState(description string, state string) error
+	VerifyDetailed() (*VerificationResult, error)
 }
 
 type Action func() error
 
-type stateAction struct {
-	setup    Action
-	teardown Action
-}
-
 type pactFileVerfier struct {
-	stateActions  map[string]*stateAction
+	stateActions  StateActions
 	provider      string
 	consumer      string
 	pactUri       string
 	pactUriConfig *PactUriConfig
+	brokerUrl     string
+	brokerConfig  *broker.BrokerConfig
 	validator     consumerValidator
 	config        *VerfierConfig
 }
@@ -43,10 +45,13 @@ func NewPactFileVerifier(setup, teardown Action, config *VerfierConfig) Verifier
 		config = DefaultVerifierConfig
 	}
 
+	validator := newConsumerValidator(setup, teardown, config.Logger)
+	validator.SetParallelism(config.Parallelism)
+
 	return &pactFileVerfier{
-		validator:    newConsumerValidator(setup, teardown, config.Logger),
+		validator:    validator,
 		config:       config,
-		stateActions: make(map[string]*stateAction),
+		stateActions: make(StateActions),
 	}
 }
 
@@ -68,7 +73,7 @@ func (v *pactFileVerfier) ServiceProvider(providerName string, c *http.Client, u
 func (v *pactFileVerfier) ProviderState(state string, setup, teardown Action) Verifier {
 	//sacrificed empty state validation in favour of chaining
 	if state != "" {
-		v.stateActions[state] = &stateAction{setup: setup, teardown: teardown}
+		v.stateActions[state] = &StateAction{Setup: setup, Teardown: teardown}
 	}
 	return v
 }
@@ -89,6 +94,90 @@ func (v *pactFileVerfier) PactUri(uri string, config *PactUriConfig) Verifier {
 	return v
 }
 
+//PactBroker sources the pacts to verify from a Pact Broker's pacts-for-verification endpoint,
+//using config's consumer version selectors, instead of a single fixed PactUri. When
+//config.PublishResults is set, the outcome of each pact is published back to the broker as
+//part of Verify().
+func (v *pactFileVerfier) PactBroker(brokerURL string, config *BrokerConfig) Verifier {
+	if config == nil {
+		config = DefaultBrokerConfig
+	}
+	v.brokerUrl = brokerURL
+	v.brokerConfig = config
+	return v
+}
+
+//ProviderStateIsolation marks a state registered with ProviderState as safe to verify
+//concurrently with other interactions, including others declaring the same state, e.g. because its setup and
+//teardown actions only manage read-only fixtures. States with no setup/teardown action at
+//all are always safe and don't need this.
+func (v *pactFileVerfier) ProviderStateIsolation(state string) Verifier {
+	v.validator.MarkStateParallelSafe(state)
+	return v
+}
+
+//ProviderStateURL configures a state-change endpoint on the running provider that is called
+//before/after each interaction that declares a provider state, instead of requiring the provider to be embedded in the same
+//process as the verifier. A state registered with ProviderState still takes precedence over
+//this URL for its specific state; the URL only covers states with no registered callback.
+func (v *pactFileVerfier) ProviderStateURL(u *url.URL, config *StateChangeConfig) Verifier {
+	if config == nil {
+		config = DefaultStateChangeConfig
+	}
+	v.validator.StateChangeEndpoint(u, config)
+	return v
+}
+
+func (v *pactFileVerfier) verifyFromBroker() (*VerificationResult, error) {
+	//broker verification sources pacts for every consumer matching the broker's selectors, so
+	//HonoursPactWith is neither required nor consulted here
+	if err := v.verifyProviderState(); err != nil {
+		return nil, err
+	}
+
+	client := broker.NewClient(v.brokerUrl, v.brokerConfig)
+	pacts, err := client.PactsForVerification(v.provider, v.config.IncludePendingPacts, v.config.IncludeWipPactsSince)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &VerificationResult{}
+	for _, p := range pacts {
+		f, err := client.Fetch(p)
+		if err != nil {
+			return nil, err
+		}
+		if err := f.Validate(); err != nil {
+			return nil, err
+		}
+
+		pending := p.Pending() || p.WIP()
+		interactions, err := v.validator.ValidateDetailed(f, v.stateActions, pending)
+		if err != nil {
+			return nil, err
+		}
+		result.Interactions = append(result.Interactions, interactions...)
+
+		if v.brokerConfig.PublishResults {
+			publishResult := &broker.VerificationResult{Success: true}
+			for _, i := range interactions {
+				if !i.Success && !i.Pending {
+					publishResult.Success = false
+				}
+				publishResult.TestResults = append(publishResult.TestResults, broker.InteractionResult{
+					Success: i.Success,
+					Pending: i.Pending,
+				})
+			}
+			if err := client.PublishVerificationResults(p, publishResult); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return result, nil
+}
+
 //VerifyState verifies the consumer interactions for given state and/or description with the provider
 func (v *pactFileVerfier) VerifyState(description string, state string) error {
 	if err := v.verifyInternalState(); err != nil {
@@ -116,7 +205,7 @@ func (v *pactFileVerfier) VerifyState(description string, state string) error {
 	if state != "" {
 		var filteredInteractions []*consumer.Interaction
 		for _, val := range f.Interactions {
-			if val.State == state {
+			if val.StateName() == state {
 				filteredInteractions = append(filteredInteractions, val)
 			}
 		}
@@ -138,13 +227,59 @@ func (v *pactFileVerfier) VerifyState(description string, state string) error {
 
 //Verify verifies all the interactions of consumer with the provider
 func (v *pactFileVerfier) Verify() error {
+	if v.brokerConfig != nil {
+		result, err := v.verifyFromBroker()
+		if err != nil {
+			return err
+		}
+		if !result.Success() {
+			return ErrVerificationFailedNonPending
+		}
+		return nil
+	}
 	return v.VerifyState("", "")
 }
 
+//VerifyDetailed verifies all the interactions of consumer with the provider, like Verify, but
+//returns the outcome of every interaction rather than collapsing them into a single error.
+//Interactions from pending or WIP pacts are included but never cause the returned error to be set.
+func (v *pactFileVerfier) VerifyDetailed() (*VerificationResult, error) {
+	if v.brokerConfig != nil {
+		result, err := v.verifyFromBroker()
+		if err != nil {
+			return nil, err
+		}
+		if !result.Success() {
+			return result, ErrVerificationFailedNonPending
+		}
+		return result, nil
+	}
+
+	if err := v.verifyInternalState(); err != nil {
+		return nil, err
+	}
+
+	f, err := v.getPactFile()
+	if err != nil {
+		return nil, err
+	}
+
+	interactions, err := v.validator.ValidateDetailed(f, v.stateActions, false)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &VerificationResult{Interactions: interactions}
+	if !result.Success() {
+		return result, ErrVerificationFailedNonPending
+	}
+	return result, nil
+}
+
 func (v *pactFileVerfier) getPactFile() (*io.PactFile, error) {
 	var r io.PactReader
 	if io.IsWebUri(v.pactUri) {
-		r = io.NewPactWebReader(v.pactUri, v.pactUriConfig.Username, v.pactUriConfig.Password)
+		r = io.NewPactWebReader(v.pactUri, v.pactUriConfig.Username, v.pactUriConfig.Password, v.pactUriConfig.Cache)
 	} else {
 		r = io.NewPactFileReader(v.pactUri)
 	}
@@ -160,11 +295,19 @@ func (v *pactFileVerfier) getPactFile() (*io.PactFile, error) {
 	return f, nil
 }
 
+//verifyInternalState checks everything a single-pact-uri verification needs: a consumer and
+//provider name plus a usable validator
 func (v *pactFileVerfier) verifyInternalState() error {
 	if v.consumer == "" {
 		return errEmptyConsumer
 	}
+	return v.verifyProviderState()
+}
 
+//verifyProviderState checks everything a broker verification needs. It deliberately omits the
+//consumer check: a broker run sources pacts for every consumer matching its selectors, so
+//HonoursPactWith plays no part in it.
+func (v *pactFileVerfier) verifyProviderState() error {
 	if v.provider == "" {
 		return errEmptyProvider
 	}